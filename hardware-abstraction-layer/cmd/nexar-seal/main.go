@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"nexar-hal/pkg/crypto"
+)
+
+func main() {
+	keyVersion := flag.String("key-version", "", "key version to record in the sealed envelope")
+	publicKeyB64 := flag.String("public-key", "", "base64-encoded X25519 public key to seal against")
+	flag.Parse()
+
+	if *keyVersion == "" || *publicKeyB64 == "" {
+		fmt.Fprintln(os.Stderr, "usage: nexar-seal -key-version <version> -public-key <base64> < plaintext")
+		os.Exit(2)
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(*publicKeyB64)
+	if err != nil {
+		slog.Error("decoding public key", "error", err)
+		os.Exit(1)
+	}
+
+	sealer, err := crypto.NewX25519Sealer(*keyVersion, publicKey)
+	if err != nil {
+		slog.Error("creating sealer", "error", err)
+		os.Exit(1)
+	}
+
+	plaintext, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		slog.Error("reading stdin", "error", err)
+		os.Exit(1)
+	}
+
+	sealed, err := sealer.Seal(context.Background(), plaintext)
+	if err != nil {
+		slog.Error("sealing secret", "error", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(string(sealed))
+}
@@ -0,0 +1,16 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache is a namespaced key/value store with TTL support. Get decodes the
+// stored value into dst: a *[]byte receives the raw bytes as stored,
+// anything else is decoded through the Cache's codec (JSON by default).
+type Cache interface {
+	Get(ctx context.Context, key string, dst interface{}) error
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Ping(ctx context.Context) error
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// config holds the per-subsystem settings applied when a CacheManager
+// builds a new Cache instance.
+type config struct {
+	prefix string
+	ttl    time.Duration
+	codec  Codec
+	label  string
+}
+
+// Option customizes a Cache instance handed out by CacheManager.Cache.
+type Option func(*config)
+
+// WithTTL sets the default TTL applied when Set is called with ttl == 0.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) { c.ttl = ttl }
+}
+
+// WithCodec overrides the codec used to decode values that aren't *[]byte.
+func WithCodec(codec Codec) Option {
+	return func(c *config) { c.codec = codec }
+}
+
+// WithMetricsLabel sets the label kept on the Cache, in case it should
+// differ from the subsystem name it was requested under. The Cache emits
+// no metrics or log lines, so nothing reads the label.
+func WithMetricsLabel(label string) Option {
+	return func(c *config) { c.label = label }
+}
+
+// CacheManager hands out isolated, namespaced Cache instances that share a
+// single underlying Redis connection pool. Each subsystem should ask for
+// its own Cache by name rather than constructing a Redis client directly.
+type CacheManager struct {
+	client *redis.Client
+
+	mu        sync.Mutex
+	instances map[string]Cache
+}
+
+// NewCacheManager builds a CacheManager backed by a single shared Redis
+// connection pool.
+func NewCacheManager(host, password string, db int, caCert []byte) *CacheManager {
+	var tlsConfig *tls.Config
+	if len(caCert) > 0 {
+		caCertPool := x509.NewCertPool()
+		if ok := caCertPool.AppendCertsFromPEM(caCert); ok {
+			tlsConfig = &tls.Config{RootCAs: caCertPool}
+		}
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:        host,
+		Password:    password,
+		DB:          db,
+		TLSConfig:   tlsConfig,
+		PoolSize:    10,
+		PoolTimeout: time.Millisecond * 20,
+	})
+	return NewCacheManagerWithClient(client)
+}
+
+// NewCacheManagerWithClient builds a CacheManager around an existing
+// *redis.Client, so tests can point it at a miniredis instance or a fake.
+func NewCacheManagerWithClient(client *redis.Client) *CacheManager {
+	return &CacheManager{
+		client:    client,
+		instances: make(map[string]Cache),
+	}
+}
+
+// Cache returns the Cache for the given subsystem name, creating it on
+// first use with name as its key prefix and metrics label. Repeated calls
+// for the same name return the same instance, so options only take effect
+// the first time a name is requested.
+func (cm *CacheManager) Cache(name string, opts ...Option) Cache {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if c, ok := cm.instances[name]; ok {
+		return c
+	}
+
+	cfg := config{
+		prefix: name,
+		codec:  JSONCodec,
+		label:  name,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	c := &redisCache{
+		client: cm.client,
+		prefix: cfg.prefix,
+		ttl:    cfg.ttl,
+		codec:  cfg.codec,
+		label:  cfg.label,
+	}
+	cm.instances[name] = c
+	return c
+}
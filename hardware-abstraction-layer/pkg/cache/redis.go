@@ -2,80 +2,61 @@ package cache
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-var rds *redis.Client
+// redisCache is a Cache backed by a shared *redis.Client, namespaced under
+// a prefix so unrelated subsystems can't collide on keys. Instances are
+// handed out by a CacheManager, which owns the underlying connection pool.
+type redisCache struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+	codec  Codec
+	label  string
+}
 
-type Redis struct {
-	db       int
-	host     string
-	mu       sync.Mutex
-	prefix   string
-	password string
-	tls      *tls.Config
+func (r *redisCache) fullKey(key string) string {
+	return fmt.Sprintf("%s:%s", r.prefix, key)
 }
 
-func NewRedisClient(host string, password string, db int, prefix string, caCert []byte) Cache {
-	var tlsConfig *tls.Config
-	if len(caCert) > 0 {
-		caCertPool := x509.NewCertPool()
-		if ok := caCertPool.AppendCertsFromPEM(caCert); ok {
-			tlsConfig = &tls.Config{
-				RootCAs: caCertPool,
-			}
-		}
+func (r *redisCache) Get(ctx context.Context, key string, dst interface{}) error {
+	raw, err := r.client.Get(ctx, r.fullKey(key)).Bytes()
+	if err != nil {
+		return err
 	}
-	return &Redis{
-		db:       db,
-		host:     host,
-		mu:       sync.Mutex{},
-		prefix:   prefix,
-		password: password,
-		tls:      tlsConfig,
+	if b, ok := dst.(*[]byte); ok {
+		*b = raw
+		return nil
 	}
+	return r.codec.Unmarshal(raw, dst)
 }
 
-func (r *Redis) getRedisClient() *redis.Client {
-	if rds == nil {
-		rds = redis.NewClient(&redis.Options{
-			Addr:        r.host,
-			Password:    r.password,
-			DB:          r.db,
-			TLSConfig:   r.tls,
-			PoolSize:    10,
-			PoolTimeout: time.Millisecond * 20,
-		})
+func (r *redisCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := r.encode(value)
+	if err != nil {
+		return err
 	}
-	return rds
-}
-
-func (r *Redis) Get(ctx context.Context, key string) (interface{}, error) {
-	rdb := r.getRedisClient()
-	return rdb.Get(ctx, fmt.Sprintf("%s:%s", r.prefix, key)).Result()
+	if ttl == 0 {
+		ttl = r.ttl
+	}
+	return r.client.Set(ctx, r.fullKey(key), data, ttl).Err()
 }
 
-func (r *Redis) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	rdb := r.getRedisClient()
-	return rdb.Set(ctx, fmt.Sprintf("%s:%s", r.prefix, key), value, ttl).Err()
+func (r *redisCache) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, r.fullKey(key)).Err()
 }
 
-func (r *Redis) Delete(ctx context.Context, key string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	rdb := r.getRedisClient()
-	return rdb.Del(ctx, fmt.Sprintf("%s:%s", r.prefix, key)).Err()
+func (r *redisCache) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
 }
 
-func (r *Redis) Ping(ctx context.Context) error {
-	rdb := r.getRedisClient()
-	return rdb.Ping(ctx).Err()
+func (r *redisCache) encode(value interface{}) ([]byte, error) {
+	if b, ok := value.([]byte); ok {
+		return b, nil
+	}
+	return r.codec.Marshal(value)
 }
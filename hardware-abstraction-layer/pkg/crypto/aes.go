@@ -1,29 +1,198 @@
 package crypto
 
 import (
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
 	"fmt"
+	"sync"
+
 	"nexar-hal/pkg/keystore"
 )
 
+const dekSize = 32 // AES-256
+
+// envelopeVersion is bumped whenever aesHeader's shape changes in a way
+// that isn't backward compatible, so Decrypt can reject envelopes it
+// doesn't know how to read instead of misparsing them.
+const envelopeVersion = 1
+
+// defaultCompressMinSize is the plaintext size at or above which compression
+// kicks in when no WithCompression option overrides it.
+const defaultCompressMinSize = 1024
+
+// aesHeader is the wire format for an envelope-encrypted payload: the KEK
+// version it was wrapped under, the per-message DEK wrapped with that KEK,
+// the compression codec the payload was run through, and the ciphertext.
+// Both WrappedDEK and Ciphertext are nonce||ciphertext||tag as produced by
+// encrypt, so no separate nonce or tag field is needed.
 type aesHeader struct {
-	KeyVersion string `json:"key_version"`
-	Id         string `json:"id"`
-	Hash       string `json:"hash"`
+	Version     int    `json:"version"`
+	KeyVersion  string `json:"key_version"`
+	Compression string `json:"compression"`
+	WrappedDEK  []byte `json:"wrapped_dek"`
+	Ciphertext  []byte `json:"ciphertext"`
 }
 
 type aesImpl struct {
-	keyVersion string
-	keyName    string
-	keystore   keystore.Keystore
+	mu              sync.RWMutex
+	currentVersion  string
+	keyName         string
+	keystore        keystore.Keystore
+	compression     string
+	compressMinSize int
+}
+
+// Option customizes an aesImpl built by NewAESImpl.
+type Option func(*aesImpl)
+
+// WithCompression selects the compression codec ("zstd", "gzip", or
+// "none") applied to plaintext that is at least minSize bytes before it's
+// encrypted. Payloads under minSize are left uncompressed to avoid paying
+// compression overhead on small secrets.
+func WithCompression(codec string, minSize int) Option {
+	return func(a *aesImpl) {
+		a.compression = codec
+		a.compressMinSize = minSize
+	}
+}
+
+func NewAESImpl(keyVersion, keyName string, keystore keystore.Keystore, opts ...Option) Crypto {
+	a := &aesImpl{
+		currentVersion:  keyVersion,
+		keyName:         keyName,
+		keystore:        keystore,
+		compression:     CodecZstd,
+		compressMinSize: defaultCompressMinSize,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+func (a *aesImpl) Encrypt(ctx context.Context, data interface{}, saltSize int) ([]byte, error) {
+	plaintext, err := toBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	version := a.currentVersion
+	a.mu.RUnlock()
+
+	kek, err := a.fetchKEK(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+
+	codec := CodecNone
+	if a.compression != "" && a.compression != CodecNone && len(plaintext) >= a.compressMinSize {
+		codec = a.compression
+	}
+	compressed, err := compress(codec, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("compressing payload: %w", err)
+	}
+
+	dek := make([]byte, dekSize)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, fmt.Errorf("generating DEK: %w", err)
+	}
+
+	wrappedDEK, err := a.encrypt(dek, kek)
+	if err != nil {
+		return nil, fmt.Errorf("wrapping DEK: %w", err)
+	}
+
+	ciphertext, err := a.encrypt(compressed, dek)
+	if err != nil {
+		return nil, fmt.Errorf("encrypting payload: %w", err)
+	}
+
+	return json.Marshal(aesHeader{
+		Version:     envelopeVersion,
+		KeyVersion:  version,
+		Compression: codec,
+		WrappedDEK:  wrappedDEK,
+		Ciphertext:  ciphertext,
+	})
+}
+
+func (a *aesImpl) Decrypt(ctx context.Context, data []byte) (interface{}, error) {
+	var header aesHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("parsing envelope: %w", err)
+	}
+	if header.Version != envelopeVersion {
+		return nil, fmt.Errorf("unsupported envelope version %d", header.Version)
+	}
+
+	kek, err := a.fetchKEK(ctx, header.KeyVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	dek, err := a.decrypt(header.WrappedDEK, kek)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping DEK: %w", err)
+	}
+
+	compressed, err := a.decrypt(header.Ciphertext, dek)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting payload: %w", err)
+	}
+
+	plaintext, err := decompress(header.Compression, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing payload: %w", err)
+	}
+	return plaintext, nil
+}
+
+// Rotate generates a fresh KEK, publishes it to the keystore, and points
+// future Encrypt calls at the version the keystore assigned it. Backends
+// auto-version on Set, so that assigned version — not a caller-chosen
+// label — is what gets recorded as currentVersion; Ciphertexts wrapped
+// under earlier versions stay decryptable since Decrypt always looks up
+// the version recorded in the envelope.
+func (a *aesImpl) Rotate(ctx context.Context) error {
+	kek := make([]byte, dekSize)
+	if _, err := rand.Read(kek); err != nil {
+		return fmt.Errorf("generating KEK: %w", err)
+	}
+	version, err := a.keystore.Set(ctx, a.keyName, kek)
+	if err != nil {
+		return fmt.Errorf("publishing new KEK: %w", err)
+	}
+
+	a.mu.Lock()
+	a.currentVersion = version
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *aesImpl) fetchKEK(ctx context.Context, version string) ([]byte, error) {
+	raw, err := a.keystore.Get(ctx, a.keyName, version)
+	if err != nil {
+		return nil, fmt.Errorf("fetching KEK version %s: %w", version, err)
+	}
+	return toBytes(raw)
 }
 
-func NewAESImpl(keyVersion, keyName string, keystore keystore.Keystore) Crypto {
-	return &aesImpl{
-		keyVersion: keyVersion,
-		keyName:    keyName,
-		keystore:   keystore,
+// toBytes normalizes the interface{} values passed around by Crypto and
+// Keystore into a byte slice: []byte and string pass through as-is,
+// anything else is JSON-marshaled.
+func toBytes(data interface{}) ([]byte, error) {
+	switch v := data.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return json.Marshal(data)
 	}
 }
 
@@ -37,6 +206,9 @@ func (a *aesImpl) encrypt(data []byte, key []byte) ([]byte, error) {
 		return nil, err
 	}
 	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
 	ciphertext := gcm.Seal(nonce, nonce, data, nil)
 	return ciphertext, nil
 }
@@ -0,0 +1,120 @@
+package crypto
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"nexar-hal/pkg/keystore"
+)
+
+// memKeystore is a minimal in-memory Keystore that auto-increments an
+// integer version per key, mirroring how GCP and Vault version secrets
+// server-side, so tests exercise the same "caller can't pick a version"
+// contract the real backends enforce.
+type memKeystore struct {
+	versions map[string]map[string][]byte
+	next     map[string]int
+}
+
+func newMemKeystore() *memKeystore {
+	return &memKeystore{
+		versions: make(map[string]map[string][]byte),
+		next:     make(map[string]int),
+	}
+}
+
+func (m *memKeystore) Get(ctx context.Context, key, version string) (interface{}, error) {
+	versions, ok := m.versions[key]
+	if !ok {
+		return nil, fmt.Errorf("no such key %s", key)
+	}
+	value, ok := versions[version]
+	if !ok {
+		return nil, fmt.Errorf("no such version %s for key %s", version, key)
+	}
+	return value, nil
+}
+
+func (m *memKeystore) Set(ctx context.Context, key string, value interface{}, opts ...keystore.SecretOption) (string, error) {
+	data, ok := value.([]byte)
+	if !ok {
+		return "", fmt.Errorf("value must be []byte, got %T", value)
+	}
+	m.next[key]++
+	version := fmt.Sprintf("%d", m.next[key])
+	if m.versions[key] == nil {
+		m.versions[key] = make(map[string][]byte)
+	}
+	m.versions[key][version] = data
+	return version, nil
+}
+
+func (m *memKeystore) Delete(ctx context.Context, key, version string) error {
+	delete(m.versions[key], version)
+	return nil
+}
+
+func TestAESImplEncryptDecryptRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	ks := newMemKeystore()
+	version, err := ks.Set(ctx, "kek", make([]byte, dekSize))
+	if err != nil {
+		t.Fatalf("seeding KEK: %v", err)
+	}
+
+	a := NewAESImpl(version, "kek", ks)
+
+	ciphertext, err := a.Encrypt(ctx, []byte("hello world"), 0)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	plaintext, err := a.Decrypt(ctx, ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if got, ok := plaintext.([]byte); !ok || string(got) != "hello world" {
+		t.Fatalf("Decrypt returned %v, want %q", plaintext, "hello world")
+	}
+}
+
+func TestAESImplDecryptAfterRotate(t *testing.T) {
+	ctx := context.Background()
+	ks := newMemKeystore()
+	version, err := ks.Set(ctx, "kek", make([]byte, dekSize))
+	if err != nil {
+		t.Fatalf("seeding KEK: %v", err)
+	}
+
+	a := NewAESImpl(version, "kek", ks)
+
+	before, err := a.Encrypt(ctx, []byte("before rotation"), 0)
+	if err != nil {
+		t.Fatalf("Encrypt before rotation: %v", err)
+	}
+
+	if err := a.Rotate(ctx); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	after, err := a.Encrypt(ctx, []byte("after rotation"), 0)
+	if err != nil {
+		t.Fatalf("Encrypt after rotation: %v", err)
+	}
+
+	plaintext, err := a.Decrypt(ctx, before)
+	if err != nil {
+		t.Fatalf("Decrypt envelope wrapped under the pre-rotation KEK: %v", err)
+	}
+	if got, ok := plaintext.([]byte); !ok || string(got) != "before rotation" {
+		t.Fatalf("Decrypt(before) = %v, want %q", plaintext, "before rotation")
+	}
+
+	plaintext, err = a.Decrypt(ctx, after)
+	if err != nil {
+		t.Fatalf("Decrypt envelope wrapped under the post-rotation KEK: %v", err)
+	}
+	if got, ok := plaintext.([]byte); !ok || string(got) != "after rotation" {
+		t.Fatalf("Decrypt(after) = %v, want %q", plaintext, "after rotation")
+	}
+}
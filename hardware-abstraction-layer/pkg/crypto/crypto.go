@@ -5,4 +5,10 @@ import "context"
 type Crypto interface {
 	Encrypt(ctx context.Context, data interface{}, saltSize int) ([]byte, error)
 	Decrypt(ctx context.Context, data []byte) (interface{}, error)
+
+	// Rotate generates a new KEK, publishes it to the keystore, and makes
+	// the version the keystore assigned it the version used for future
+	// Encrypt calls. Ciphertexts wrapped under earlier versions remain
+	// decryptable as long as the keystore still serves those versions.
+	Rotate(ctx context.Context) error
 }
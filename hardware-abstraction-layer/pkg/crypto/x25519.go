@@ -0,0 +1,173 @@
+package crypto
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+
+	"nexar-hal/pkg/keystore"
+)
+
+// sealedVersion guards sealedHeader's wire format. A blob sealed offline
+// today may not be unsealed until long after the format has moved on, so
+// Unseal checks this before touching the keystore or the ciphertext and
+// fails loudly on a mismatch rather than risk silently misreading a
+// future layout as the current one.
+const sealedVersion = 1
+
+// AsymCrypto seals secrets against a public key with no access to the
+// keystore, and unseals them given a keystore handle that can fetch the
+// matching private key. This lets Seal run entirely offline.
+type AsymCrypto interface {
+	Seal(ctx context.Context, data interface{}) ([]byte, error)
+	Unseal(ctx context.Context, data []byte) (interface{}, error)
+}
+
+// sealedHeader is the wire format for a sealed blob: the key version to
+// fetch the private half of the recipient key from, the ephemeral public
+// key used for this one seal, and the ChaCha20-Poly1305 ciphertext.
+type sealedHeader struct {
+	Version      int    `json:"version"`
+	KeyVersion   string `json:"key_version"`
+	EphemeralPub []byte `json:"ephemeral_pub"`
+	Nonce        []byte `json:"nonce"`
+	Ciphertext   []byte `json:"ciphertext"`
+}
+
+// deriveSealKey runs the raw X25519 shared point through HKDF-SHA256,
+// binding the derived key to both public keys involved so it can't be
+// reused across a different sender/recipient pairing.
+func deriveSealKey(shared, ephemeralPub, recipientPub []byte) ([]byte, error) {
+	info := append(append([]byte{}, ephemeralPub...), recipientPub...)
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, nil, info), key); err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+	return key, nil
+}
+
+type x25519Impl struct {
+	keyVersion string
+	publicKey  []byte
+	keyName    string
+	keystore   keystore.Keystore
+}
+
+// NewX25519Sealer builds an AsymCrypto that can only Seal, against a
+// recipient public key the caller already fetched from the keystore. It
+// never touches the keystore itself, so it's safe to embed in tooling that
+// must never hold a decryption key.
+func NewX25519Sealer(keyVersion string, publicKey []byte) (AsymCrypto, error) {
+	if len(publicKey) != curve25519.PointSize {
+		return nil, fmt.Errorf("public key must be %d bytes, got %d", curve25519.PointSize, len(publicKey))
+	}
+	return &x25519Impl{keyVersion: keyVersion, publicKey: publicKey}, nil
+}
+
+// NewX25519Unsealer builds an AsymCrypto that can only Unseal, fetching
+// the private key matching a sealed blob's key_version from the keystore
+// on demand.
+func NewX25519Unsealer(keyName string, keystore keystore.Keystore) AsymCrypto {
+	return &x25519Impl{keyName: keyName, keystore: keystore}
+}
+
+func (x *x25519Impl) Seal(ctx context.Context, data interface{}) ([]byte, error) {
+	if x.publicKey == nil {
+		return nil, fmt.Errorf("sealer has no recipient public key configured")
+	}
+	plaintext, err := toBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeralPriv := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(ephemeralPriv); err != nil {
+		return nil, fmt.Errorf("generating ephemeral key: %w", err)
+	}
+	ephemeralPub, err := curve25519.X25519(ephemeralPriv, curve25519.Basepoint)
+	if err != nil {
+		return nil, fmt.Errorf("deriving ephemeral public key: %w", err)
+	}
+	shared, err := curve25519.X25519(ephemeralPriv, x.publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("computing shared secret: %w", err)
+	}
+	key, err := deriveSealKey(shared, ephemeralPub, x.publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, fmt.Errorf("initializing cipher: %w", err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ciphertext := aead.Seal(nil, nonce, plaintext, nil)
+
+	return json.Marshal(sealedHeader{
+		Version:      sealedVersion,
+		KeyVersion:   x.keyVersion,
+		EphemeralPub: ephemeralPub,
+		Nonce:        nonce,
+		Ciphertext:   ciphertext,
+	})
+}
+
+func (x *x25519Impl) Unseal(ctx context.Context, data []byte) (interface{}, error) {
+	if x.keystore == nil {
+		return nil, fmt.Errorf("unsealer has no keystore configured")
+	}
+
+	var header sealedHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("parsing sealed envelope: %w", err)
+	}
+	if header.Version != sealedVersion {
+		return nil, fmt.Errorf("unsupported sealed envelope version %d", header.Version)
+	}
+
+	rawPrivateKey, err := x.keystore.Get(ctx, x.keyName, header.KeyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("fetching private key version %s: %w", header.KeyVersion, err)
+	}
+	privateKey, err := toBytes(rawPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(privateKey) != curve25519.ScalarSize {
+		return nil, fmt.Errorf("private key must be %d bytes, got %d", curve25519.ScalarSize, len(privateKey))
+	}
+
+	recipientPub, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, fmt.Errorf("deriving recipient public key: %w", err)
+	}
+	shared, err := curve25519.X25519(privateKey, header.EphemeralPub)
+	if err != nil {
+		return nil, fmt.Errorf("computing shared secret: %w", err)
+	}
+	key, err := deriveSealKey(shared, header.EphemeralPub, recipientPub)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, fmt.Errorf("initializing cipher: %w", err)
+	}
+
+	plaintext, err := aead.Open(nil, header.Nonce, header.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting sealed payload: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,90 @@
+package crypto
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+func TestX25519ImplSealUnsealRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	privateKey := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(privateKey); err != nil {
+		t.Fatalf("generating private key: %v", err)
+	}
+	publicKey, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		t.Fatalf("deriving public key: %v", err)
+	}
+
+	ks := newMemKeystore()
+	version, err := ks.Set(ctx, "recipient", privateKey)
+	if err != nil {
+		t.Fatalf("seeding private key: %v", err)
+	}
+
+	sealer, err := NewX25519Sealer(version, publicKey)
+	if err != nil {
+		t.Fatalf("NewX25519Sealer: %v", err)
+	}
+	unsealer := NewX25519Unsealer("recipient", ks)
+
+	blob, err := sealer.Seal(ctx, []byte("top secret"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	plaintext, err := unsealer.Unseal(ctx, blob)
+	if err != nil {
+		t.Fatalf("Unseal: %v", err)
+	}
+	if got, ok := plaintext.([]byte); !ok || string(got) != "top secret" {
+		t.Fatalf("Unseal returned %v, want %q", plaintext, "top secret")
+	}
+}
+
+func TestX25519ImplUnsealRejectsTamperedCiphertext(t *testing.T) {
+	ctx := context.Background()
+
+	privateKey := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(privateKey); err != nil {
+		t.Fatalf("generating private key: %v", err)
+	}
+	publicKey, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		t.Fatalf("deriving public key: %v", err)
+	}
+
+	ks := newMemKeystore()
+	version, err := ks.Set(ctx, "recipient", privateKey)
+	if err != nil {
+		t.Fatalf("seeding private key: %v", err)
+	}
+
+	sealer, err := NewX25519Sealer(version, publicKey)
+	if err != nil {
+		t.Fatalf("NewX25519Sealer: %v", err)
+	}
+	unsealer := NewX25519Unsealer("recipient", ks)
+
+	blob, err := sealer.Seal(ctx, []byte("top secret"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	var header sealedHeader
+	if err := json.Unmarshal(blob, &header); err != nil {
+		t.Fatalf("parsing sealed blob: %v", err)
+	}
+	header.Ciphertext[len(header.Ciphertext)-1] ^= 0xFF
+	blob, err = json.Marshal(header)
+	if err != nil {
+		t.Fatalf("re-encoding tampered blob: %v", err)
+	}
+
+	if _, err := unsealer.Unseal(ctx, blob); err == nil {
+		t.Fatal("Unseal succeeded on tampered ciphertext, want error")
+	}
+}
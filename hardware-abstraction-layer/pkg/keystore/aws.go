@@ -0,0 +1,109 @@
+package keystore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+type awsSecretsManager struct {
+	client    *secretsmanager.Client
+	namespace string
+}
+
+// NewAWSSecretsManager builds a Keystore backed by AWS Secrets Manager,
+// namespacing every key under namespace.
+func NewAWSSecretsManager(cfg aws.Config, namespace string) Keystore {
+	return &awsSecretsManager{
+		client:    secretsmanager.NewFromConfig(cfg),
+		namespace: namespace,
+	}
+}
+
+func (a *awsSecretsManager) secretID(key string) string {
+	return fmt.Sprintf("%s:%s", a.namespace, key)
+}
+
+func (a *awsSecretsManager) Get(ctx context.Context, key, version string) (interface{}, error) {
+	id := a.secretID(key)
+	input := &secretsmanager.GetSecretValueInput{SecretId: aws.String(id)}
+	if version != "" {
+		input.VersionId = aws.String(version)
+	} else {
+		input.VersionStage = aws.String("AWSCURRENT")
+	}
+
+	out, err := a.client.GetSecretValue(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("getting secret %s: %w", id, err)
+	}
+	if out.SecretBinary != nil {
+		return out.SecretBinary, nil
+	}
+	return []byte(aws.ToString(out.SecretString)), nil
+}
+
+// Set writes value under key and returns the VersionId Secrets Manager
+// assigned it. Labels passed via WithLabels become tags on newly created
+// secrets; replication and IAM options are ignored since Secrets Manager
+// has no per-secret equivalent of either.
+func (a *awsSecretsManager) Set(ctx context.Context, key string, value interface{}, opts ...SecretOption) (string, error) {
+	id := a.secretID(key)
+	data, ok := value.([]byte)
+	if !ok {
+		return "", fmt.Errorf("secret %s: value must be []byte, got %T", id, value)
+	}
+
+	out, err := a.client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(id),
+		SecretBinary: data,
+	})
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		o := applySecretOptions(opts)
+		createOut, createErr := a.client.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+			Name:         aws.String(id),
+			SecretBinary: data,
+			Tags:         labelsToTags(o.labels),
+		})
+		if createErr != nil {
+			return "", fmt.Errorf("writing secret %s: %w", id, createErr)
+		}
+		return aws.ToString(createOut.VersionId), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("writing secret %s: %w", id, err)
+	}
+	return aws.ToString(out.VersionId), nil
+}
+
+func labelsToTags(labels map[string]string) []types.Tag {
+	if len(labels) == 0 {
+		return nil
+	}
+	tags := make([]types.Tag, 0, len(labels))
+	for k, v := range labels {
+		tags = append(tags, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return tags
+}
+
+func (a *awsSecretsManager) Delete(ctx context.Context, key, version string) error {
+	id := a.secretID(key)
+	if version != "" {
+		// Secrets Manager has no API to delete a single version: versions are
+		// only retired by staging a replacement over them. Deleting a whole
+		// secret is all the service exposes, so a version-scoped delete is a
+		// caller error rather than something we can silently approximate.
+		return fmt.Errorf("deleting secret %s: AWS Secrets Manager does not support deleting a single version", id)
+	}
+	_, err := a.client.DeleteSecret(ctx, &secretsmanager.DeleteSecretInput{SecretId: aws.String(id)})
+	if err != nil {
+		return fmt.Errorf("deleting secret %s: %w", id, err)
+	}
+	return nil
+}
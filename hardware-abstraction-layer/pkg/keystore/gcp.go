@@ -5,24 +5,87 @@ import (
 	"fmt"
 	"hash/crc32"
 	"log/slog"
+	"path"
+	"time"
+
 	"nexar-hal/pkg/cache"
 
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	gax "github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// CallOptions holds the gax retry policy applied to each Secret Manager
+// RPC the google backend makes.
+type CallOptions struct {
+	Get    []gax.CallOption
+	Set    []gax.CallOption
+	Delete []gax.CallOption
+	List   []gax.CallOption
+}
+
+// DefaultCallOptions retries UNAVAILABLE, DEADLINE_EXCEEDED and INTERNAL
+// with exponential backoff, matching the retry policy Google's own client
+// libraries ship for Secret Manager.
+func DefaultCallOptions() *CallOptions {
+	retry := []gax.CallOption{
+		gax.WithRetry(func() gax.Retryer {
+			return gax.OnCodes([]codes.Code{
+				codes.Unavailable,
+				codes.DeadlineExceeded,
+				codes.Internal,
+			}, gax.Backoff{
+				Initial:    200 * time.Millisecond,
+				Max:        10 * time.Second,
+				Multiplier: 1.3,
+			})
+		}),
+	}
+	return &CallOptions{Get: retry, Set: retry, Delete: retry, List: retry}
+}
+
 type google struct {
-	projectID string
-	cache     cache.Cache
-	namespace string
+	projectID   string
+	cache       cache.Cache
+	namespace   string
+	client      *secretmanager.Client
+	callOptions *CallOptions
+}
+
+// Option customizes a google Keystore built by NewGoogleSecretManager.
+type Option func(*google)
+
+// WithCallOptions overrides the retry policy applied to every Secret
+// Manager RPC.
+func WithCallOptions(co *CallOptions) Option {
+	return func(g *google) { g.callOptions = co }
 }
 
-func NewGoogleSecretManager(projectID, prefix string, cache cache.Cache) Keystore {
-	return &google{
-		projectID: projectID,
-		cache:     cache,
-		namespace: prefix,
+// NewGoogleSecretManager opens a Secret Manager client and reuses it for
+// the lifetime of the returned Keystore, rather than dialing one per call.
+func NewGoogleSecretManager(ctx context.Context, projectID, prefix string, cache cache.Cache, opts ...Option) (Keystore, error) {
+	client, err := secretmanager.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating Secret Manager client: %w", err)
+	}
+	g := &google{
+		projectID:   projectID,
+		cache:       cache,
+		namespace:   prefix,
+		client:      client,
+		callOptions: DefaultCallOptions(),
+	}
+	for _, opt := range opts {
+		opt(g)
 	}
+	return g, nil
+}
+
+// Close releases the underlying Secret Manager client.
+func (g *google) Close() error {
+	return g.client.Close()
 }
 
 func (g *google) Get(ctx context.Context, key, version string) (interface{}, error) {
@@ -31,27 +94,17 @@ func (g *google) Get(ctx context.Context, key, version string) (interface{}, err
 	}
 	key = fmt.Sprintf("projects/%s/secrets/%s/versions/%s", g.projectID, fmt.Sprintf("%s:%s", g.namespace, key), version)
 	if g.cache != nil {
-		if data, err := g.cache.Get(ctx, key); err == nil {
+		var data []byte
+		if err := g.cache.Get(ctx, key, &data); err == nil {
 			return data, nil
 		} else {
 			slog.DebugContext(ctx, "cache miss", "key", key, "error", err)
 		}
 	}
-	client, err := secretmanager.NewClient(ctx)
-	if err != nil {
-		slog.ErrorContext(ctx, "Error creating Secret Manager client", "error", err)
-		return nil, err
-	}
-	defer func(client *secretmanager.Client) {
-		err := client.Close()
-		if err != nil {
-			slog.ErrorContext(ctx, "Error closing Secret Manager client", "error", err)
-		}
-	}(client)
 	req := &secretmanagerpb.AccessSecretVersionRequest{
 		Name: key,
 	}
-	resp, err := client.AccessSecretVersion(ctx, req)
+	resp, err := g.client.AccessSecretVersion(ctx, req, g.callOptions.Get...)
 	if err != nil {
 		slog.ErrorContext(ctx, "Error accessing secret version", "error", err, "key", key)
 		return nil, err
@@ -71,66 +124,139 @@ func (g *google) Get(ctx context.Context, key, version string) (interface{}, err
 	return resp.Payload.Data, nil
 }
 
-func (g *google) Set(ctx context.Context, key string, value interface{}) error {
-	client, err := secretmanager.NewClient(ctx)
-	if err != nil {
-		slog.ErrorContext(ctx, "Error creating Secret Manager client", "error", err)
-		return err
+// Set adds value as a new version of secret key, creating the secret first
+// if this is its first write, and returns the version number Secret
+// Manager assigned. A plain AddSecretVersion is tried first so that a
+// rotation's repeated Set calls against an already-created secret don't
+// pay for (and fail on) a redundant CreateSecret; WithLabels,
+// WithReplication and WithIAMBindings only take effect on that first
+// creation and have no effect on a secret that already exists.
+func (g *google) Set(ctx context.Context, key string, value interface{}, opts ...SecretOption) (string, error) {
+	data, ok := value.([]byte)
+	if !ok {
+		return "", fmt.Errorf("secret %s: value must be []byte, got %T", key, value)
 	}
-	defer func(client *secretmanager.Client) {
-		err := client.Close()
-		if err != nil {
-			slog.ErrorContext(ctx, "Error closing Secret Manager client", "error", err)
-		}
-	}(client)
-	req := &secretmanagerpb.CreateSecretRequest{
+	secretName := fmt.Sprintf("projects/%s/secrets/%s", g.projectID, fmt.Sprintf("%s:%s", g.namespace, key))
+
+	if version, err := g.addVersion(ctx, secretName, data); err == nil {
+		return version, nil
+	} else if status.Code(err) != codes.NotFound {
+		return "", err
+	}
+
+	o := applySecretOptions(opts)
+	labels := map[string]string{"namespace": g.namespace}
+	for k, v := range o.labels {
+		labels[k] = v
+	}
+
+	secret, err := g.client.CreateSecret(ctx, &secretmanagerpb.CreateSecretRequest{
 		Parent:   fmt.Sprintf("projects/%s", g.projectID),
 		SecretId: fmt.Sprintf("%s:%s", g.namespace, key),
 		Secret: &secretmanagerpb.Secret{
-			Replication: &secretmanagerpb.Replication{
-				Replication: &secretmanagerpb.Replication_Automatic_{
-					Automatic: &secretmanagerpb.Replication_Automatic{},
-				},
-			},
-			Labels: map[string]string{
-				"namespace": g.namespace,
-			},
+			Replication: replicationPolicy(o.replication),
+			Labels:      labels,
 		},
-	}
-	secret, err := client.CreateSecret(ctx, req)
+	}, g.callOptions.Set...)
 	if err != nil {
 		slog.ErrorContext(ctx, "Error creating secret", "error", err, "key", key)
-		return err
+		return "", err
 	}
-	secretVersionReq := &secretmanagerpb.AddSecretVersionRequest{
-		Parent: secret.Name,
+
+	version, err := g.addVersion(ctx, secret.Name, data)
+	if err != nil {
+		return "", err
+	}
+
+	if len(o.iamBindings) > 0 {
+		// SetIamPolicy replaces the whole policy, so every binding goes into
+		// one request.
+		bindings := make([]*secretmanagerpb.Binding, 0, len(o.iamBindings))
+		for _, binding := range o.iamBindings {
+			bindings = append(bindings, &secretmanagerpb.Binding{
+				Role:    binding.Role,
+				Members: binding.Members,
+			})
+		}
+		if _, err := g.client.SetIamPolicy(ctx, &secretmanagerpb.SetIamPolicyRequest{
+			Resource: secret.Name,
+			Policy:   &secretmanagerpb.Policy{Bindings: bindings},
+		}, g.callOptions.Set...); err != nil {
+			slog.ErrorContext(ctx, "Error setting IAM policy", "error", err, "key", key)
+			return "", fmt.Errorf("setting IAM policy on %s: %w", secret.Name, err)
+		}
+	}
+	return version, nil
+}
+
+// addVersion adds data as a new version of the secret at secretName and
+// returns the version number Secret Manager assigned it.
+func (g *google) addVersion(ctx context.Context, secretName string, data []byte) (string, error) {
+	resp, err := g.client.AddSecretVersion(ctx, &secretmanagerpb.AddSecretVersionRequest{
+		Parent: secretName,
 		Payload: &secretmanagerpb.SecretPayload{
-			Data: value.([]byte),
+			Data: data,
+		},
+	}, g.callOptions.Set...)
+	if err != nil {
+		return "", err
+	}
+	return path.Base(resp.Name), nil
+}
+
+func replicationPolicy(policy *ReplicationPolicy) *secretmanagerpb.Replication {
+	if policy == nil || policy.Automatic {
+		return &secretmanagerpb.Replication{
+			Replication: &secretmanagerpb.Replication_Automatic_{
+				Automatic: &secretmanagerpb.Replication_Automatic{},
+			},
+		}
+	}
+	replicas := make([]*secretmanagerpb.Replication_UserManaged_Replica, 0, len(policy.Locations))
+	for _, location := range policy.Locations {
+		replicas = append(replicas, &secretmanagerpb.Replication_UserManaged_Replica{Location: location})
+	}
+	return &secretmanagerpb.Replication{
+		Replication: &secretmanagerpb.Replication_UserManaged_{
+			UserManaged: &secretmanagerpb.Replication_UserManaged{Replicas: replicas},
 		},
 	}
-	_, err = client.AddSecretVersion(ctx, secretVersionReq)
-	return err
 }
 
 func (g *google) Delete(ctx context.Context, key, version string) error {
-	if version != "" {
+	if version == "" {
 		version = "latest"
 	}
-	client, err := secretmanager.NewClient(ctx)
-	if err != nil {
-		slog.ErrorContext(ctx, "Error creating Secret Manager client", "error", err)
-		return err
-	}
-	defer func(client *secretmanager.Client) {
-		err := client.Close()
-		if err != nil {
-			slog.ErrorContext(ctx, "Error closing Secret Manager client", "error", err)
-		}
-	}(client)
 	secretName := fmt.Sprintf("projects/%s/secrets/%s/versions/%s", g.projectID, fmt.Sprintf("%s:%s", g.namespace, key), version)
 	req := &secretmanagerpb.DestroySecretVersionRequest{
 		Name: secretName,
 	}
-	_, err = client.DestroySecretVersion(ctx, req)
+	_, err := g.client.DestroySecretVersion(ctx, req, g.callOptions.Delete...)
 	return err
 }
+
+// SecretNameIterator walks the secrets in a namespace, returned by List.
+type SecretNameIterator struct {
+	it *secretmanager.SecretIterator
+}
+
+// Next returns the next secret name, or iterator.Done once exhausted.
+func (it *SecretNameIterator) Next() (string, error) {
+	secret, err := it.it.Next()
+	if err != nil {
+		return "", err
+	}
+	return secret.Name, nil
+}
+
+// List enumerates the secrets under this namespace's project, optionally
+// narrowed by a Secret Manager filter expression. It does not restrict the
+// listing to this namespace: without a filter, every secret in the project
+// is returned, including those of other namespaces.
+func (g *google) List(ctx context.Context, filter string) *SecretNameIterator {
+	req := &secretmanagerpb.ListSecretsRequest{
+		Parent: fmt.Sprintf("projects/%s", g.projectID),
+		Filter: filter,
+	}
+	return &SecretNameIterator{it: g.client.ListSecrets(ctx, req, g.callOptions.List...)}
+}
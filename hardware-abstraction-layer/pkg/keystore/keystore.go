@@ -4,6 +4,11 @@ import "context"
 
 type Keystore interface {
 	Get(ctx context.Context, key, version string) (interface{}, error)
-	Set(ctx context.Context, key string, value interface{}) error
+	// Set writes value under key and returns the version the backend
+	// assigned it (backends auto-version rather than accepting a
+	// caller-chosen version id), so callers that need to address this
+	// exact write later — e.g. for rotation — must use the returned
+	// version rather than inventing one of their own.
+	Set(ctx context.Context, key string, value interface{}, opts ...SecretOption) (string, error)
 	Delete(ctx context.Context, key, version string) error
 }
@@ -0,0 +1,81 @@
+package keystore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// multi composes several Keystore backends behind one handle. Reads try
+// each backend in order and return the first success, so a read-through
+// migration can point new backends ahead of the old one. Writes fan out to
+// every backend, so a secret set once stays available on all of them while
+// operators cut traffic over.
+type multi struct {
+	backends []Keystore
+}
+
+// NewMulti builds a Keystore that tries backends in order for reads and
+// writes to all of them, letting operators migrate providers without
+// downtime.
+func NewMulti(backends ...Keystore) Keystore {
+	return &multi{backends: backends}
+}
+
+func (m *multi) Get(ctx context.Context, key, version string) (interface{}, error) {
+	var errs error
+	for _, b := range m.backends {
+		value, err := b.Get(ctx, key, version)
+		if err == nil {
+			return value, nil
+		}
+		errs = errors.Join(errs, err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, fmt.Errorf("no backend served key %s: %w", key, errs)
+}
+
+// Set fans out to every backend and returns the version reported by the
+// first one that succeeds (its leftmost-surviving backend is the de facto
+// primary). Backends assign their own version ids independently, so a
+// caller that needs to read back this exact write must go through the
+// same backend ordering rather than assuming every backend used the same
+// version string. Failures of individual backends are logged; an error is
+// returned only when no backend accepted the write.
+func (m *multi) Set(ctx context.Context, key string, value interface{}, opts ...SecretOption) (string, error) {
+	var errs error
+	var version string
+	for _, b := range m.backends {
+		v, err := b.Set(ctx, key, value, opts...)
+		if err != nil {
+			slog.ErrorContext(ctx, "backend failed to set secret", "key", key, "error", err)
+			errs = errors.Join(errs, err)
+		} else if version == "" {
+			version = v
+		}
+		if ctx.Err() != nil {
+			return version, ctx.Err()
+		}
+	}
+	if version == "" {
+		return "", fmt.Errorf("no backend wrote key %s: %w", key, errs)
+	}
+	return version, nil
+}
+
+func (m *multi) Delete(ctx context.Context, key, version string) error {
+	var errs error
+	for _, b := range m.backends {
+		if err := b.Delete(ctx, key, version); err != nil {
+			slog.ErrorContext(ctx, "backend failed to delete secret", "key", key, "error", err)
+			errs = errors.Join(errs, err)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return errs
+}
@@ -0,0 +1,111 @@
+package keystore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// fakeKeystore is a minimal in-memory Keystore used to test composites like
+// multi without depending on any real backend.
+type fakeKeystore struct {
+	data map[string]map[string][]byte
+	next map[string]int
+	fail bool
+}
+
+func newFakeKeystore() *fakeKeystore {
+	return &fakeKeystore{data: make(map[string]map[string][]byte), next: make(map[string]int)}
+}
+
+func (f *fakeKeystore) Get(ctx context.Context, key, version string) (interface{}, error) {
+	versions, ok := f.data[key]
+	if !ok {
+		return nil, fmt.Errorf("no such key %s", key)
+	}
+	value, ok := versions[version]
+	if !ok {
+		return nil, fmt.Errorf("no such version %s for key %s", version, key)
+	}
+	return value, nil
+}
+
+func (f *fakeKeystore) Set(ctx context.Context, key string, value interface{}, opts ...SecretOption) (string, error) {
+	if f.fail {
+		return "", fmt.Errorf("backend unavailable")
+	}
+	data, ok := value.([]byte)
+	if !ok {
+		return "", fmt.Errorf("value must be []byte, got %T", value)
+	}
+	f.next[key]++
+	version := fmt.Sprintf("%d", f.next[key])
+	if f.data[key] == nil {
+		f.data[key] = make(map[string][]byte)
+	}
+	f.data[key][version] = data
+	return version, nil
+}
+
+func (f *fakeKeystore) Delete(ctx context.Context, key, version string) error {
+	delete(f.data[key], version)
+	return nil
+}
+
+func TestMultiSetGetRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	a, b := newFakeKeystore(), newFakeKeystore()
+	m := NewMulti(a, b)
+
+	version, err := m.Set(ctx, "kek", []byte("binary-secret"))
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	got, err := m.Get(ctx, "kek", version)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(got.([]byte)) != "binary-secret" {
+		t.Fatalf("Get returned %v, want %q", got, "binary-secret")
+	}
+
+	if _, ok := b.data["kek"][version]; !ok {
+		t.Fatal("Set did not fan out to the second backend")
+	}
+}
+
+func TestMultiSetReturnsFirstSuccessfulVersion(t *testing.T) {
+	ctx := context.Background()
+	failing := newFakeKeystore()
+	failing.fail = true
+	ok := newFakeKeystore()
+	m := NewMulti(failing, ok)
+
+	version, err := m.Set(ctx, "kek", []byte("binary-secret"))
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if version != "1" {
+		t.Fatalf("Set returned version %q, want the surviving backend's version %q", version, "1")
+	}
+}
+
+func TestMultiGetFallsBackToNextBackend(t *testing.T) {
+	ctx := context.Background()
+	empty, populated := newFakeKeystore(), newFakeKeystore()
+	m := NewMulti(empty, populated)
+
+	version, err := populated.Set(ctx, "kek", []byte("binary-secret"))
+	if err != nil {
+		t.Fatalf("seeding populated backend: %v", err)
+	}
+
+	got, err := m.Get(ctx, "kek", version)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(got.([]byte)) != "binary-secret" {
+		t.Fatalf("Get returned %v, want %q", got, "binary-secret")
+	}
+}
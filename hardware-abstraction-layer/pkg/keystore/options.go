@@ -0,0 +1,54 @@
+package keystore
+
+// ReplicationPolicy controls how a backend that supports multi-region
+// replication stores a secret. Automatic lets the backend pick locations;
+// Locations pins it to a user-managed set (ignored when Automatic is true).
+type ReplicationPolicy struct {
+	Automatic bool
+	Locations []string
+}
+
+// IAMBinding grants role to members on a secret created via Set. Backends
+// that have no notion of per-secret IAM ignore bindings.
+type IAMBinding struct {
+	Role    string
+	Members []string
+}
+
+// secretOptions collects the optional metadata a backend may apply when
+// Set creates a new secret. Not every backend honors every field.
+type secretOptions struct {
+	labels      map[string]string
+	replication *ReplicationPolicy
+	iamBindings []IAMBinding
+}
+
+// SecretOption customizes how Set creates a secret.
+type SecretOption func(*secretOptions)
+
+// WithLabels attaches key/value labels (or tags, on backends that call them
+// that) to a secret created via Set.
+func WithLabels(labels map[string]string) SecretOption {
+	return func(o *secretOptions) { o.labels = labels }
+}
+
+// WithReplication selects the replication policy for a secret created via
+// Set, on backends that support it.
+func WithReplication(policy ReplicationPolicy) SecretOption {
+	return func(o *secretOptions) { o.replication = &policy }
+}
+
+// WithIAMBindings grants the given role/member bindings on a secret
+// immediately after Set creates it, on backends that support per-secret
+// IAM policies.
+func WithIAMBindings(bindings ...IAMBinding) SecretOption {
+	return func(o *secretOptions) { o.iamBindings = bindings }
+}
+
+func applySecretOptions(opts []SecretOption) secretOptions {
+	var o secretOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,99 @@
+package keystore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// fakeVaultKV serves just enough of Vault's KV v2 HTTP API (PUT/GET
+// /v1/{mount}/data/{path}) to exercise vaultKeystore.Set/Get against a real
+// HTTP round trip, including the JSON encoding the vault-api client applies
+// to the request/response bodies.
+func fakeVaultKV(t *testing.T) *httptest.Server {
+	t.Helper()
+	versions := map[string]map[string]interface{}{}
+	next := map[string]int{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+
+		switch r.Method {
+		case http.MethodPut:
+			var body struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			next[path]++
+			version := next[path]
+			if versions[path] == nil {
+				versions[path] = map[string]interface{}{}
+			}
+			versions[path][strconv.Itoa(version)] = body.Data
+
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"version": version,
+				},
+			})
+		case http.MethodGet:
+			byVersion := versions[path]
+			if byVersion == nil {
+				http.NotFound(w, r)
+				return
+			}
+			version := r.URL.Query().Get("version")
+			if version == "" {
+				version = strconv.Itoa(next[path])
+			}
+			data, ok := byVersion[version]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"data":     data,
+					"metadata": map[string]interface{}{"version": version},
+				},
+			})
+		default:
+			http.Error(w, "unsupported method", http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func TestVaultKeystoreSetGetRoundTrip(t *testing.T) {
+	server := fakeVaultKV(t)
+	defer server.Close()
+
+	ks, err := NewVaultKeystore(server.URL, "test-token", "secret", "ns")
+	if err != nil {
+		t.Fatalf("NewVaultKeystore: %v", err)
+	}
+
+	binary := []byte{0x00, 0x01, 0xff, 0xfe, 'h', 'i'}
+	ctx := context.Background()
+	version, err := ks.Set(ctx, "kek", binary)
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	got, err := ks.Get(ctx, "kek", version)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	gotBytes, ok := got.([]byte)
+	if !ok {
+		t.Fatalf("Get returned %T, want []byte", got)
+	}
+	if string(gotBytes) != string(binary) {
+		t.Fatalf("Get round-trip = %v, want %v", gotBytes, binary)
+	}
+}
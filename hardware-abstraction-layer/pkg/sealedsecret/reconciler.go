@@ -0,0 +1,38 @@
+package sealedsecret
+
+import (
+	"context"
+	"fmt"
+
+	"nexar-hal/pkg/crypto"
+	"nexar-hal/pkg/keystore"
+)
+
+// Reconciler unseals sealed-secret blobs on demand and republishes their
+// plaintext into a Keystore, so the running service reads an ordinary
+// secret at boot without ever handling the key used to seal it offline.
+type Reconciler struct {
+	unsealer crypto.AsymCrypto
+	store    keystore.Keystore
+}
+
+func NewReconciler(unsealer crypto.AsymCrypto, store keystore.Keystore) *Reconciler {
+	return &Reconciler{unsealer: unsealer, store: store}
+}
+
+// Reconcile unseals blob and writes the resulting plaintext into the
+// keystore under destKey.
+func (r *Reconciler) Reconcile(ctx context.Context, destKey string, blob []byte) error {
+	plaintext, err := r.unsealer.Unseal(ctx, blob)
+	if err != nil {
+		return fmt.Errorf("unsealing %s: %w", destKey, err)
+	}
+	data, ok := plaintext.([]byte)
+	if !ok {
+		return fmt.Errorf("unsealing %s: unexpected plaintext type %T", destKey, plaintext)
+	}
+	if _, err := r.store.Set(ctx, destKey, data); err != nil {
+		return fmt.Errorf("publishing unsealed secret %s: %w", destKey, err)
+	}
+	return nil
+}